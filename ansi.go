@@ -0,0 +1,435 @@
+package giuplus
+
+import (
+	"strconv"
+	"strings"
+
+	g "github.com/AllenDang/giu"
+	"github.com/AllenDang/imgui-go"
+	"github.com/rivo/uniseg"
+)
+
+// ansiStyle is the active SGR style applied to a run of text. bold and
+// italic are parsed and carried through but not yet rendered: plain ImGui
+// text has no bold/italic font variant wired up in this package, so they are
+// stored for a future renderer that can swap fonts.
+type ansiStyle struct {
+	fg, bg       imgui.Vec4
+	hasFg, hasBg bool
+	bold, faint  bool
+	italic       bool
+	underline    bool
+	invert       bool
+}
+
+// ansiRun is a contiguous span of text sharing one ansiStyle.
+type ansiRun struct {
+	style ansiStyle
+	text  string
+}
+
+// ansiCluster is a single grapheme cluster tagged with the style it was
+// parsed under, the unit wrapANSIClusters wraps on.
+type ansiCluster struct {
+	text  string
+	style ansiStyle
+}
+
+// SetANSIRender switches a read-only TextEditor to a custom renderer that
+// interprets a subset of ANSI SGR escape sequences (CSI "[...m": reset,
+// bold/faint/italic/underline/invert, 8/16-color, 256-color, and truecolor)
+// instead of showing the raw escape codes. It only takes effect while
+// ReadOnly is also true, turning the editor into a log/terminal viewer.
+func (e *TextEditor) SetANSIRender(on bool) {
+	e.ansiRender = on
+}
+
+// ANSIRender reports whether ANSI rendering is enabled.
+func (e *TextEditor) ANSIRender() bool {
+	return e.ansiRender
+}
+
+// buildANSI renders e.text as styled runs instead of handing it to InputText.
+// Selection and copy still work via an invisible InputTextMultiline overlaid
+// on top of the styled text.
+func (e *TextEditor) buildANSI() {
+	width := e.width
+	if width <= 0 {
+		width = 400
+	}
+
+	lines := wrapANSIClusters(flattenANSIClusters(parseANSI(e.text)), width)
+
+	start := imgui.CursorScreenPos()
+	for _, line := range lines {
+		if e.alignment != AlignLeft {
+			var lineWidth float32
+			for _, c := range line {
+				lineWidth += TextWidth(c.text)
+			}
+			offset := width - lineWidth
+			if e.alignment == AlignCenter {
+				offset /= 2
+			}
+			if offset > 0 {
+				pos := imgui.CursorScreenPos()
+				imgui.SetCursorScreenPos(imgui.Vec2{X: pos.X + offset, Y: pos.Y})
+			}
+		}
+		drawn := false
+		for _, run := range groupRuns(line) {
+			if run.text == "" {
+				continue
+			}
+			if drawn {
+				imgui.SameLine()
+			}
+			drawANSIRun(run)
+			drawn = true
+		}
+		if !drawn {
+			// Force a vertical advance for blank lines; a line with no runs
+			// draws nothing, so without this the next line would overlap it.
+			g.Label("").Build()
+		}
+	}
+
+	// Transparent overlay so the user can still select and copy the plain text.
+	// It must be bound to the de-ANSI'd, identically-wrapped text rather than
+	// e.text: imgui's own auto-wrap on the raw bytes (escapes included) would
+	// not line up with the styled runs above it, and a copy would pick up the
+	// literal escape bytes instead of plain text.
+	plain := plainText(lines)
+	imgui.SetCursorScreenPos(start)
+	imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{})
+	imgui.PushStyleColor(imgui.StyleColorFrameBg, imgui.Vec4{})
+	imgui.PushStyleColor(imgui.StyleColorBorder, imgui.Vec4{})
+	g.InputTextMultiline(&plain).Size(width, e.height).
+		Flags(imgui.InputTextFlagsReadOnly).Build()
+	imgui.PopStyleColorV(3)
+}
+
+// plainText joins wrapped display lines back into a newline-separated string
+// with all ANSI styling stripped, one line per entry in lines, matching the
+// wrapping drawANSIRun rendered above.
+func plainText(lines [][]ansiCluster) string {
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		var b strings.Builder
+		for _, c := range line {
+			b.WriteString(c.text)
+		}
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, "\n")
+}
+
+func drawANSIRun(run ansiRun) {
+	fg, bg, hasBg := resolveANSIColors(run.style)
+	top := imgui.CursorScreenPos()
+	textWidth := TextWidth(run.text)
+	_, lineHeight := g.CalcTextSize("M")
+
+	if hasBg {
+		imgui.GetWindowDrawList().AddRectFilled(
+			top, imgui.Vec2{X: top.X + textWidth, Y: top.Y + lineHeight}, bg, 0, 0)
+	}
+
+	imgui.PushStyleColor(imgui.StyleColorText, fg)
+	g.Label(run.text).Build()
+	imgui.PopStyleColor()
+
+	if run.style.underline {
+		y := top.Y + lineHeight
+		imgui.GetWindowDrawList().AddLine(
+			imgui.Vec2{X: top.X, Y: y}, imgui.Vec2{X: top.X + textWidth, Y: y}, fg, 1)
+	}
+}
+
+// resolveANSIColors applies invert and faint on top of the run's parsed
+// foreground/background, the same way a terminal emulator would.
+func resolveANSIColors(s ansiStyle) (fg, bg imgui.Vec4, hasBg bool) {
+	fg, hasFg := s.fg, s.hasFg
+	bg, hasBg = s.bg, s.hasBg
+	if !hasFg {
+		fg = imgui.Vec4{X: 1, Y: 1, Z: 1, W: 1}
+	}
+	if s.invert {
+		fg, bg = bg, fg
+		if !hasBg {
+			// the default background is black, the counterpart of the white default foreground
+			fg = imgui.Vec4{X: 0, Y: 0, Z: 0, W: 1}
+		}
+		hasBg = true
+	}
+	if s.faint {
+		fg.W *= 0.6
+	}
+	return fg, bg, hasBg
+}
+
+// flattenANSIClusters expands each run into its grapheme clusters, each
+// tagged with the style it was parsed under.
+func flattenANSIClusters(runs []ansiRun) []ansiCluster {
+	var out []ansiCluster
+	for _, run := range runs {
+		gr := uniseg.NewGraphemes(run.text)
+		for gr.Next() {
+			out = append(out, ansiCluster{text: gr.Str(), style: run.style})
+		}
+	}
+	return out
+}
+
+// wrapANSIClusters groups clusters into display lines, breaking a line once it
+// grows wider than width at the same opportunities (space, tab, hyphen, CJK
+// ideograph) as the multiline editor's own word-wrap in giuplus.go. A line
+// with no such opportunity stays wider than width.
+func wrapANSIClusters(clusters []ansiCluster, width float32) [][]ansiCluster {
+	var lines [][]ansiCluster
+	lineStart := 0
+	breakAt := -1
+	lineWidth := float32(0)
+
+	for i, c := range clusters {
+		if c.text == "\n" {
+			lines = append(lines, clusters[lineStart:i])
+			lineStart, breakAt, lineWidth = i+1, -1, 0
+			continue
+		}
+
+		cw := TextWidth(c.text)
+		if isBreak, consume := breakOpportunity(c.text); isBreak {
+			if consume {
+				breakAt = i
+			} else {
+				breakAt = i + 1
+			}
+		}
+
+		if lineWidth+cw > width && breakAt > lineStart {
+			lines = append(lines, clusters[lineStart:breakAt])
+			lineStart = breakAt
+			if lineStart <= i && (clusters[lineStart].text == " " || clusters[lineStart].text == "\t") {
+				lineStart++
+			}
+			breakAt, lineWidth = -1, 0
+			for _, kept := range clusters[lineStart : i+1] {
+				lineWidth += TextWidth(kept.text)
+			}
+			continue
+		}
+		lineWidth += cw
+	}
+	lines = append(lines, clusters[lineStart:])
+	return lines
+}
+
+// groupRuns collapses consecutive same-style clusters back into ansiRuns for rendering.
+func groupRuns(clusters []ansiCluster) []ansiRun {
+	var runs []ansiRun
+	var cur strings.Builder
+	var style ansiStyle
+	started := false
+	for _, c := range clusters {
+		if !started {
+			style, started = c.style, true
+		} else if c.style != style {
+			runs = append(runs, ansiRun{style: style, text: cur.String()})
+			cur.Reset()
+			style = c.style
+		}
+		cur.WriteString(c.text)
+	}
+	if started {
+		runs = append(runs, ansiRun{style: style, text: cur.String()})
+	}
+	return runs
+}
+
+// parseANSI splits s into runs of (style, text), interpreting CSI "ESC [ ... m"
+// SGR sequences and leaving all other bytes as plain text: reset (0), bold
+// (1), faint (2), italic (3), underline (4), invert (7), the 8 standard and 8
+// bright foreground/background colors (30-37/90-97, 40-47/100-107), 256-color
+// (38;5;n / 48;5;n), and truecolor (38;2;r;g;b / 48;2;r;g;b).
+func parseANSI(s string) []ansiRun {
+	var runs []ansiRun
+	var cur strings.Builder
+	style := ansiStyle{}
+
+	flush := func() {
+		if cur.Len() > 0 {
+			runs = append(runs, ansiRun{style: style, text: cur.String()})
+			cur.Reset()
+		}
+	}
+
+	i := 0
+	for i < len(s) {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			end := i + 2
+			for end < len(s) && (s[end] == ';' || (s[end] >= '0' && s[end] <= '9')) {
+				end++
+			}
+			if end < len(s) && s[end] == 'm' {
+				flush()
+				style = applySGR(style, s[i+2:end])
+				i = end + 1
+				continue
+			}
+			// not an SGR sequence, or truncated: keep its bytes as literal text
+		}
+		cur.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return runs
+}
+
+func applySGR(style ansiStyle, params string) ansiStyle {
+	codes := strings.Split(params, ";")
+	for idx := 0; idx < len(codes); idx++ {
+		n, err := strconv.Atoi(codes[idx])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 0:
+			style = ansiStyle{}
+		case n == 1:
+			style.bold = true
+		case n == 2:
+			style.faint = true
+		case n == 3:
+			style.italic = true
+		case n == 4:
+			style.underline = true
+		case n == 7:
+			style.invert = true
+		case n == 22:
+			style.bold, style.faint = false, false
+		case n == 23:
+			style.italic = false
+		case n == 24:
+			style.underline = false
+		case n == 27:
+			style.invert = false
+		case n >= 30 && n <= 37:
+			style.fg, style.hasFg = ansi8Color(n-30, false), true
+		case n >= 90 && n <= 97:
+			style.fg, style.hasFg = ansi8Color(n-90, true), true
+		case n == 39:
+			style.hasFg = false
+		case n >= 40 && n <= 47:
+			style.bg, style.hasBg = ansi8Color(n-40, false), true
+		case n >= 100 && n <= 107:
+			style.bg, style.hasBg = ansi8Color(n-100, true), true
+		case n == 49:
+			style.hasBg = false
+		case n == 38 || n == 48:
+			var color imgui.Vec4
+			var ok bool
+			idx, color, ok = parseExtendedColor(codes, idx)
+			if ok {
+				if n == 38 {
+					style.fg, style.hasFg = color, true
+				} else {
+					style.bg, style.hasBg = color, true
+				}
+			}
+		}
+	}
+	return style
+}
+
+// parseExtendedColor reads a 38;5;n / 38;2;r;g;b (or 48;...) sequence starting
+// at codes[idx] == "38" (or "48") and returns the index of its last consumed
+// element, the decoded color, and whether parsing succeeded.
+func parseExtendedColor(codes []string, idx int) (int, imgui.Vec4, bool) {
+	if idx+1 >= len(codes) {
+		return idx, imgui.Vec4{}, false
+	}
+	mode, err := strconv.Atoi(codes[idx+1])
+	if err != nil {
+		return idx, imgui.Vec4{}, false
+	}
+	switch mode {
+	case 5:
+		if idx+2 >= len(codes) {
+			return idx, imgui.Vec4{}, false
+		}
+		n, err := strconv.Atoi(codes[idx+2])
+		if err != nil {
+			return idx, imgui.Vec4{}, false
+		}
+		return idx + 2, ansi256Color(n), true
+	case 2:
+		if idx+4 >= len(codes) {
+			return idx, imgui.Vec4{}, false
+		}
+		r, err1 := strconv.Atoi(codes[idx+2])
+		grn, err2 := strconv.Atoi(codes[idx+3])
+		b, err3 := strconv.Atoi(codes[idx+4])
+		if err1 != nil || err2 != nil || err3 != nil {
+			return idx, imgui.Vec4{}, false
+		}
+		return idx + 4, imgui.Vec4{X: float32(r) / 255, Y: float32(grn) / 255, Z: float32(b) / 255, W: 1}, true
+	}
+	return idx, imgui.Vec4{}, false
+}
+
+// ansiBasePalette is the standard 8-color ANSI palette (black through white).
+var ansiBasePalette = [8]imgui.Vec4{
+	{X: 0, Y: 0, Z: 0, W: 1},
+	{X: 0.80, Y: 0, Z: 0, W: 1},
+	{X: 0, Y: 0.80, Z: 0, W: 1},
+	{X: 0.80, Y: 0.80, Z: 0, W: 1},
+	{X: 0, Y: 0, Z: 0.80, W: 1},
+	{X: 0.80, Y: 0, Z: 0.80, W: 1},
+	{X: 0, Y: 0.80, Z: 0.80, W: 1},
+	{X: 0.80, Y: 0.80, Z: 0.80, W: 1},
+}
+
+func ansi8Color(n int, bright bool) imgui.Vec4 {
+	c := ansiBasePalette[n]
+	if bright {
+		if c.X == 0 {
+			c.X = 0.5
+		} else {
+			c.X = 1
+		}
+		if c.Y == 0 {
+			c.Y = 0.5
+		} else {
+			c.Y = 1
+		}
+		if c.Z == 0 {
+			c.Z = 0.5
+		} else {
+			c.Z = 1
+		}
+	}
+	return c
+}
+
+func ansi256Color(n int) imgui.Vec4 {
+	switch {
+	case n < 8:
+		return ansi8Color(n, false)
+	case n < 16:
+		return ansi8Color(n-8, true)
+	case n < 232:
+		n -= 16
+		r, grn, b := (n/36)%6, (n/6)%6, n%6
+		step := func(v int) float32 {
+			if v == 0 {
+				return 0
+			}
+			return float32(55+v*40) / 255
+		}
+		return imgui.Vec4{X: step(r), Y: step(grn), Z: step(b), W: 1}
+	default: // 232-255: grayscale ramp
+		level := float32(8+(n-232)*10) / 255
+		return imgui.Vec4{X: level, Y: level, Z: level, W: 1}
+	}
+}
@@ -0,0 +1,240 @@
+package giuplus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	g "github.com/AllenDang/giu"
+	"github.com/AllenDang/imgui-go"
+)
+
+// Completion is a single candidate offered by a Completer.
+type Completion struct {
+	Text  string // text inserted in place of the completed range
+	Label string // text shown in the popup; defaults to Text when empty
+}
+
+// Completer supplies completion candidates for the text currently being edited.
+// prefixStart is the byte offset where the replaced range begins; accepting a
+// candidate replaces text[prefixStart:cursor] with candidate.Text.
+type Completer interface {
+	Complete(text string, cursor int) (prefixStart int, candidates []Completion)
+}
+
+// FilterMode selects how NewPrefixCompleter narrows its word list.
+type FilterMode int
+
+const (
+	// FilterPrefix keeps candidates whose text starts with the word being typed.
+	FilterPrefix FilterMode = iota
+	// FilterFuzzy keeps candidates in which the letters of the word being typed appear, in order.
+	FilterFuzzy
+)
+
+// prefixCompleter completes from a fixed, in-memory word list.
+type prefixCompleter struct {
+	words  []string
+	filter FilterMode
+}
+
+// NewPrefixCompleter returns a Completer that offers words from the given list
+// matching the word currently being typed at the cursor.
+func NewPrefixCompleter(words ...string) *prefixCompleter {
+	return &prefixCompleter{words: words}
+}
+
+// SetFilterMode switches the completer between prefix and fuzzy matching.
+func (c *prefixCompleter) SetFilterMode(m FilterMode) {
+	c.filter = m
+}
+
+// Complete implements Completer.
+func (c *prefixCompleter) Complete(text string, cursor int) (int, []Completion) {
+	start := completionWordStart(text, cursor)
+	word := text[start:cursor]
+	var out []Completion
+	for _, w := range c.words {
+		if matchesFilter(c.filter, w, word) {
+			out = append(out, Completion{Text: w})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Text < out[j].Text })
+	return start, out
+}
+
+// FuncCompleter adapts one or more completion functions into a Completer. The
+// functions are tried in order and the first to return any candidates wins.
+type FuncCompleter struct {
+	fns []func(text string, cursor int) (int, []Completion)
+}
+
+// NewFuncCompleter builds a Completer from one or more completion functions.
+func NewFuncCompleter(fn ...func(text string, cursor int) (int, []Completion)) *FuncCompleter {
+	return &FuncCompleter{fns: fn}
+}
+
+// Complete implements Completer.
+func (c *FuncCompleter) Complete(text string, cursor int) (int, []Completion) {
+	for _, fn := range c.fns {
+		if start, candidates := fn(text, cursor); len(candidates) > 0 {
+			return start, candidates
+		}
+	}
+	return cursor, nil
+}
+
+// SetCompleter installs c as the editor's autocomplete source and enables the
+// Tab-triggered completion popup. Passing nil disables completion.
+func (e *TextEditor) SetCompleter(c Completer) {
+	e.completer = c
+	e.completionOpen = false
+}
+
+// SetMaxCompletionRows caps how many candidates the popup shows before scrolling.
+// n <= 0 falls back to a default of 8.
+func (e *TextEditor) SetMaxCompletionRows(n int) {
+	e.maxCompletionRows = n
+}
+
+// SetOnAccept sets the callback invoked whenever a completion candidate is accepted.
+func (e *TextEditor) SetOnAccept(fn func(Completion)) {
+	e.onAccept = fn
+}
+
+// completionCallback handles the InputTextFlagsCallbackCompletion event fired by
+// imgui when the user presses Tab inside the editor.
+func (e *TextEditor) completionCallback(data imgui.InputTextCallbackData) int32 {
+	e.openCompletion(string(data.Buffer()), int(data.CursorPos()))
+	return 0
+}
+
+// openCompletion asks the completer for candidates at cursor and, if any are
+// found, opens the popup.
+func (e *TextEditor) openCompletion(text string, cursor int) {
+	if e.completer == nil {
+		return
+	}
+	start, items := e.completer.Complete(text, cursor)
+	if len(items) == 0 {
+		e.completionOpen = false
+		return
+	}
+	e.completionPrefixStart = start
+	e.completionCursor = cursor
+	e.completionItems = items
+	e.completionSel = 0
+	e.completionOpen = true
+}
+
+// buildCompletionPopup renders the floating candidate list and handles its
+// navigation and acceptance keys. The list is placed just below the editor
+// widget (the last item drawn), at its left edge; it does not follow the
+// caret's position.
+func (e *TextEditor) buildCompletionPopup() {
+	if len(e.completionItems) == 0 {
+		e.completionOpen = false
+		return
+	}
+
+	pos := imgui.GetItemRectMin()
+	pos.Y += imgui.GetItemRectSize().Y
+	imgui.SetNextWindowPos(pos)
+
+	flags := imgui.WindowFlagsNoTitleBar | imgui.WindowFlagsNoResize |
+		imgui.WindowFlagsNoMove | imgui.WindowFlagsAlwaysAutoResize |
+		imgui.WindowFlagsNoFocusOnAppearing
+	imgui.BeginV(fmt.Sprintf("##completion%p", e), nil, int(flags))
+	defer imgui.End()
+
+	switch {
+	case g.IsKeyPressed(g.KeyEscape):
+		e.completionOpen = false
+		return
+	case g.IsKeyPressed(g.KeyDown) || isCtrlKeyPressed(g.KeyN):
+		e.completionSel = (e.completionSel + 1) % len(e.completionItems)
+	case g.IsKeyPressed(g.KeyUp) || isCtrlKeyPressed(g.KeyP):
+		e.completionSel = (e.completionSel - 1 + len(e.completionItems)) % len(e.completionItems)
+	case g.IsKeyPressed(g.KeyEnter) || g.IsKeyPressed(g.KeyTab):
+		e.acceptCompletion()
+		return
+	}
+
+	rows := e.maxCompletionRows
+	if rows <= 0 {
+		rows = 8
+	}
+	if rows > len(e.completionItems) {
+		rows = len(e.completionItems)
+	}
+	childHeight := float32(rows) * imgui.TextLineHeightWithSpacing()
+	imgui.BeginChildV(fmt.Sprintf("##completionList%p", e), imgui.Vec2{X: 0, Y: childHeight}, false, 0)
+	for i, item := range e.completionItems {
+		label := item.Label
+		if label == "" {
+			label = item.Text
+		}
+		if imgui.SelectableV(label, i == e.completionSel, 0, imgui.Vec2{}) {
+			e.completionSel = i
+			e.acceptCompletion()
+		}
+	}
+	imgui.EndChild()
+}
+
+// acceptCompletion replaces the completed range with the selected candidate and
+// closes the popup.
+func (e *TextEditor) acceptCompletion() {
+	defer func() { e.completionOpen = false }()
+	if e.completionSel < 0 || e.completionSel >= len(e.completionItems) {
+		return
+	}
+	item := e.completionItems[e.completionSel]
+	prefix := e.text[:e.completionPrefixStart]
+	suffix := e.text[e.completionCursor:]
+	e.text = prefix + item.Text + suffix
+	e.selStart = len(prefix) + len(item.Text)
+	e.selEnd = e.selStart
+	// The live imgui buffer only changes from inside a callback, so the result
+	// is queued for the next CallbackAlways (see applyPendingRestore).
+	e.pendingRestore = &undoSnapshot{e.text, e.selStart, e.selEnd}
+	if e.onAccept != nil {
+		e.onAccept(item)
+	}
+}
+
+func completionWordStart(text string, cursor int) int {
+	i := cursor
+	for i > 0 && !strings.ContainsRune(" \t\n", rune(text[i-1])) {
+		i--
+	}
+	return i
+}
+
+func matchesFilter(mode FilterMode, candidate, word string) bool {
+	if word == "" {
+		return true
+	}
+	switch mode {
+	case FilterFuzzy:
+		return fuzzyMatch(candidate, word)
+	default:
+		return strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word))
+	}
+}
+
+func fuzzyMatch(candidate, word string) bool {
+	word = strings.ToLower(word)
+	candidate = strings.ToLower(candidate)
+	i := 0
+	for _, c := range candidate {
+		if i < len(word) && rune(word[i]) == c {
+			i++
+		}
+	}
+	return i == len(word)
+}
+
+func isCtrlKeyPressed(key g.Key) bool {
+	return g.IsKeyPressed(key) && (g.IsKeyDown(g.KeyLeftControl) || g.IsKeyDown(g.KeyRightControl))
+}
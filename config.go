@@ -0,0 +1,129 @@
+package giuplus
+
+import (
+	"unicode/utf8"
+
+	g "github.com/AllenDang/giu"
+	"github.com/AllenDang/imgui-go"
+)
+
+// Alignment selects the horizontal text alignment of a TextEditor.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// SetReadOnly makes the editor's text non-editable, mirroring Gio's editor.ReadOnly.
+func (e *TextEditor) SetReadOnly(on bool) {
+	e.readOnly = on
+}
+
+// ReadOnly reports whether the editor is read-only.
+func (e *TextEditor) ReadOnly() bool {
+	return e.readOnly
+}
+
+// SetPassword masks the editor's text. Since persisting a masked editor's
+// submissions would leak the password to disk, enabling it also detaches any
+// history file set via SetHistoryFile, and SetHistoryFile itself refuses to
+// attach a new one while Password stays true.
+func (e *TextEditor) SetPassword(on bool) {
+	e.password = on
+	if on {
+		e.historyFile = ""
+	}
+}
+
+// Password reports whether the editor masks its text.
+func (e *TextEditor) Password() bool {
+	return e.password
+}
+
+// SetAlignment sets the horizontal text alignment. Plain InputText widgets
+// cannot align their text; this only has a visible effect once SetANSIRender
+// switches the editor to the custom renderer, which offsets each styled line.
+// The invisible selection overlay of that renderer stays left-aligned.
+func (e *TextEditor) SetAlignment(a Alignment) {
+	e.alignment = a
+}
+
+// Alignment returns the editor's configured horizontal text alignment.
+func (e *TextEditor) Alignment() Alignment {
+	return e.alignment
+}
+
+// SetLineHeight adds sp pixels of extra vertical spacing between wrapped
+// lines in multiline mode, on top of the font's natural line height.
+func (e *TextEditor) SetLineHeight(sp float32) {
+	e.lineHeight = sp
+}
+
+// SetLineHeightScale scales the font's natural line height by scale (1.0 is
+// the font's own spacing) in multiline mode.
+func (e *TextEditor) SetLineHeightScale(scale float32) {
+	e.lineHeightScale = scale
+}
+
+// SetMaxLength caps the editor's text at n runes. n <= 0 means unlimited.
+func (e *TextEditor) SetMaxLength(n int) {
+	e.maxLength = n
+}
+
+// MaxLength returns the editor's configured maximum length in runes, or 0 if unlimited.
+func (e *TextEditor) MaxLength() int {
+	return e.maxLength
+}
+
+// SetPlaceholder sets greyed hint text rendered via InputTextWithHint while
+// the buffer is empty. Only single-line editors show it; the multiline
+// widget is built without a hint.
+func (e *TextEditor) SetPlaceholder(s string) {
+	e.placeholder = s
+}
+
+// Placeholder returns the editor's configured hint text.
+func (e *TextEditor) Placeholder() string {
+	return e.placeholder
+}
+
+// configFlags returns the InputText flags contributed by ReadOnly and
+// Password.
+func (e *TextEditor) configFlags() g.InputTextFlags {
+	var flags g.InputTextFlags
+	if e.readOnly {
+		flags |= imgui.InputTextFlagsReadOnly
+	}
+	if e.password {
+		flags |= imgui.InputTextFlagsPassword
+	}
+	return flags
+}
+
+// charFilterCallback enforces SetMaxLength for the single-line widget, which
+// has no char-filter pipeline of its own (the multiline path enforces it
+// inside WrapInputtextMultiline alongside the pivot-newline handling).
+func (e *TextEditor) charFilterCallback(data imgui.InputTextCallbackData) int32 {
+	if e.maxLength > 0 && utf8.RuneCountInString(e.text) >= e.maxLength {
+		data.SetEventChar(0)
+	}
+	return 0
+}
+
+// pushLineHeightStyle applies the configured extra line spacing for
+// multiline editors and reports whether a style var was pushed and needs
+// popping after the widget builds.
+func (e *TextEditor) pushLineHeightStyle() bool {
+	if !e.multiline || (e.lineHeight == 0 && e.lineHeightScale == 0) {
+		return false
+	}
+	_, lineHeight := g.CalcTextSize("M")
+	extra := e.lineHeight
+	if e.lineHeightScale != 0 {
+		extra += lineHeight * (e.lineHeightScale - 1)
+	}
+	imgui.PushStyleVarVec2(imgui.StyleVarItemSpacing, imgui.Vec2{X: 0, Y: extra})
+	return true
+}
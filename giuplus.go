@@ -2,9 +2,13 @@ package giuplus
 
 import (
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	g "github.com/AllenDang/giu"
 	"github.com/AllenDang/imgui-go"
+	"github.com/rivo/uniseg"
 )
 
 type TextEditor struct {
@@ -17,6 +21,36 @@ type TextEditor struct {
 	selStart   int
 	selEnd     int
 	onActivate func(e *TextEditor)
+
+	completer             Completer
+	maxCompletionRows     int
+	onAccept              func(Completion)
+	completionOpen        bool
+	completionItems       []Completion
+	completionSel         int
+	completionPrefixStart int
+	completionCursor      int
+
+	maxUndo        int
+	undoStack      []undoSnapshot
+	redoStack      []undoSnapshot
+	lastSnapshot   time.Time
+	pendingRestore *undoSnapshot
+
+	history      []string
+	historyIdx   int
+	historyDraft string
+	historyFile  string
+
+	readOnly        bool
+	password        bool
+	alignment       Alignment
+	lineHeight      float32
+	lineHeightScale float32
+	maxLength       int
+	placeholder     string
+
+	ansiRender bool
 }
 
 // NewTextEditor creates a new text editor with given height and width. The onActivate callback
@@ -52,30 +86,60 @@ func (e *TextEditor) SetOnActivate(callback func(e *TextEditor)) {
 // Widget returns the undlerying GUI widget, which is either a InputText or InputTextMultiline.
 func (e *TextEditor) Widget() g.Widget {
 	if e.multiline {
-		widget := g.InputTextMultiline(&e.text).Size(-1, 100).
-			Flags(imgui.InputTextFlagsCallbackAlways | imgui.InputTextFlagsCallbackCharFilter)
+		var flags g.InputTextFlags = imgui.InputTextFlagsCallbackAlways | imgui.InputTextFlagsCallbackCharFilter | e.configFlags()
+		if e.completer != nil {
+			flags |= imgui.InputTextFlagsCallbackCompletion
+		}
+		widget := g.InputTextMultiline(&e.text).Size(-1, 100).Flags(flags)
 		cbwidget := func(data imgui.InputTextCallbackData) int32 {
+			if data.EventFlag() == imgui.InputTextFlagsCallbackCompletion {
+				return e.completionCallback(data)
+			}
 			return WrapInputtextMultiline(e, data)
 		}
 		fullwidget := widget.Callback(cbwidget)
 		return fullwidget
 	}
-	widget := g.InputText(&e.text).Size(-1).
+	var flags g.InputTextFlags = imgui.InputTextFlagsCallbackAlways | imgui.InputTextFlagsCallbackCharFilter | e.configFlags()
+	if e.completer != nil {
+		flags |= imgui.InputTextFlagsCallbackCompletion
+	}
+	widget := g.InputText(&e.text)
+	if e.placeholder != "" {
+		widget = widget.Hint(e.placeholder)
+	}
+	return widget.Size(-1).Flags(flags).
 		Callback(func(data imgui.InputTextCallbackData) int32 {
 			switch data.EventFlag() {
 			case imgui.InputTextFlagsCallbackAlways:
 				e.selStart = data.SelectionStart()
 				e.selEnd = data.SelectionEnd()
+				e.handleEditingKeys(data)
+				e.recordUndoSnapshot(time.Now())
 				e.onActivate(e)
+			case imgui.InputTextFlagsCallbackCompletion:
+				return e.completionCallback(data)
+			case imgui.InputTextFlagsCallbackCharFilter:
+				return e.charFilterCallback(data)
 			}
 			return 0
 		})
-	return widget
 }
 
 // Build builds the widget's layout. This is to satisfy Giu's custom widget interface.
 func (e *TextEditor) Build() {
+	if e.readOnly && e.ansiRender {
+		e.buildANSI()
+		return
+	}
+	pushedLineHeight := e.pushLineHeightStyle()
 	e.Widget().Build()
+	if pushedLineHeight {
+		imgui.PopStyleVar()
+	}
+	if e.completionOpen {
+		e.buildCompletionPopup()
+	}
 }
 
 // SetText sets the editor text to the given UTF-8 string.
@@ -123,12 +187,21 @@ func (e *TextEditor) SetSize(width, height float32) {
 func WrapInputtextMultiline(e *TextEditor, data imgui.InputTextCallbackData) int32 {
 	switch data.EventFlag() {
 	case imgui.InputTextFlagsCallbackCharFilter:
+		if e.maxLength > 0 && utf8.RuneCountInString(e.text) >= e.maxLength {
+			data.SetEventChar(0)
+			break
+		}
 		c := data.EventChar()
 		if c == '\n' {
 			data.SetEventChar('\u07FF') // pivot character 2-bytes in UTF-8
 		}
 
 	case imgui.InputTextFlagsCallbackAlways:
+		e.selStart = data.SelectionStart()
+		e.selEnd = data.SelectionEnd()
+		e.handleEditingKeys(data)
+		e.recordUndoSnapshot(time.Now())
+
 		// 0. turn every pivot byte sequence into \r\n
 		buff := data.Buffer()
 		buff2 := []byte(strings.ReplaceAll(string(buff), "\u07FF", "\r\n"))
@@ -151,29 +224,134 @@ func WrapInputtextMultiline(e *TextEditor, data imgui.InputTextCallbackData) int
 				}
 			}
 		}
-		// 2. word break the whole buffer with the standard greedy algorithm
-		nl := 0
-		spc := 0
+		// 2. word break the whole buffer on grapheme-cluster boundaries so that no
+		// rune, combining mark, or ZWJ sequence is ever split. planWordWrap decides
+		// where (the decision is pure and unit-testable); applying a decision past
+		// the first one needs its byte offset shifted by the bytes any earlier
+		// decision already inserted.
 		w := g.GetWidgetWidth(e.Widget())
-		for i, c := range buff {
-			if c == 10 {
-				nl = i
-			}
-			if c == 32 {
-				spc = i
-			}
-			if TextWidth(string(buff[nl:i])) > w && spc > 0 {
-				buff[spc] = 10
+		breaks := planWordWrap(data.Buffer(), w, func(cluster string) float32 {
+			cw, _ := g.CalcTextSize(cluster)
+			return cw
+		})
+		shift := 0
+		for _, br := range breaks {
+			pos := br.pos + shift
+			if br.consume {
+				data.Buffer()[pos] = '\n'
 				data.MarkBufferModified()
+			} else {
+				data.InsertBytes(pos, []byte("\n"))
+				shift++
 			}
 		}
 	}
 	return 0
 }
 
-// TextWidth returns the width of the given text.
+// wrapBreak is one line-break decision produced by planWordWrap, as a byte
+// offset into the buffer planWordWrap was called with.
+type wrapBreak struct {
+	pos     int
+	consume bool // true: overwrite buf[pos] with '\n' (same length); false: insert '\n' at buf[pos] (grows by one byte)
+}
+
+// planWordWrap walks buf grapheme cluster by grapheme cluster and decides
+// where to break a line once its visual width, as measured by measure,
+// exceeds width. measure is called per cluster in place of g.CalcTextSize so
+// the algorithm can be driven by a cheap stand-in in tests instead of
+// requiring a live imgui context. It never proposes a break inside a
+// cluster, and only at the opportunities breakOpportunity recognizes
+// (spaces, tabs, hyphens, CJK ideographs): a line with no such opportunity
+// stays wider than width, and a hyphen or ideograph that itself crosses
+// width stays at the end of its line.
+func planWordWrap(buf []byte, width float32, measure func(cluster string) float32) []wrapBreak {
+	var breaks []wrapBreak
+	lineStart := 0
+	lineWidth := float32(0)
+	breakPos := -1
+	breakConsume := false
+	pos := 0
+	state := -1
+	for pos < len(buf) {
+		if buf[pos] == '\n' {
+			lineStart, pos = pos+1, pos+1
+			lineWidth, breakPos, state = 0, -1, -1
+			continue
+		}
+		// uniseg.Step advances by grapheme cluster over the remaining byte
+		// slice directly, so this stays linear in the buffer length; slicing
+		// and re-stringifying the tail on every cluster (as a naive
+		// FirstGraphemeClusterInString(string(buf[pos:]), -1) loop would)
+		// is quadratic.
+		clusterBytes, _, _, newState := uniseg.Step(buf[pos:], state)
+		state = newState
+		cluster := string(clusterBytes)
+		clusterLen := len(clusterBytes)
+		lineWidth += measure(cluster)
+
+		if isBreak, consume := breakOpportunity(cluster); isBreak {
+			if consume {
+				breakPos, breakConsume = pos, true
+			} else {
+				breakPos, breakConsume = pos+clusterLen, false
+			}
+		}
+
+		if lineWidth > width && breakPos > lineStart {
+			breaks = append(breaks, wrapBreak{pos: breakPos, consume: breakConsume})
+			lineStart = breakPos
+			if breakConsume {
+				lineStart++
+			}
+			lineWidth = textWidthWith(string(buf[lineStart:pos+clusterLen]), measure)
+			breakPos = -1
+			state = -1
+		}
+		pos += clusterLen
+	}
+	return breaks
+}
+
+// isCJKIdeograph reports whether the grapheme cluster is a single CJK ideograph,
+// which per UAX #14 permits a line break immediately after it.
+func isCJKIdeograph(cluster string) bool {
+	r := []rune(cluster)
+	return len(r) == 1 && unicode.Is(unicode.Han, r[0])
+}
+
+// breakOpportunity classifies a grapheme cluster against the minimal UAX #14
+// subset this package implements (spaces, tabs, hyphens, and CJK ideographs).
+// consume reports whether the cluster itself is dropped when breaking there
+// (true for whitespace) or kept with the break inserted right after it.
+func breakOpportunity(cluster string) (isBreak, consume bool) {
+	switch {
+	case cluster == " " || cluster == "\t":
+		return true, true
+	case cluster == "-" || isCJKIdeograph(cluster):
+		return true, false
+	}
+	return false, false
+}
+
+// TextWidth returns the visual width of s, measured grapheme cluster by grapheme
+// cluster rather than by rune, so multi-rune clusters (flags, ZWJ family emoji,
+// combining accents) are measured as the single glyph they render as.
 func TextWidth(s string) float32 {
-	w, _ := g.CalcTextSize(s)
+	return textWidthWith(s, func(cluster string) float32 {
+		cw, _ := g.CalcTextSize(cluster)
+		return cw
+	})
+}
+
+// textWidthWith is TextWidth with the per-cluster measurement injected, so
+// planWordWrap's tests can drive it without a live imgui context.
+func textWidthWith(s string, measure func(cluster string) float32) float32 {
+	var w float32
+	gr := uniseg.NewGraphemes(s)
+	for gr.Next() {
+		w += measure(gr.Str())
+	}
 	return w
 }
 
@@ -0,0 +1,248 @@
+package giuplus
+
+import (
+	"bufio"
+	"os"
+	"time"
+
+	g "github.com/AllenDang/giu"
+	"github.com/AllenDang/imgui-go"
+)
+
+// undoDebounce is the minimum gap between two undo snapshots; edits closer
+// together than this collapse into the same snapshot instead of each getting
+// their own undo step.
+const undoDebounce = 500 * time.Millisecond
+
+// undoSnapshot captures the buffer and selection at a point in time.
+type undoSnapshot struct {
+	text     string
+	selStart int
+	selEnd   int
+}
+
+// SetMaxUndo sets how many undo snapshots are retained. n <= 0 disables and
+// clears the undo/redo stacks.
+func (e *TextEditor) SetMaxUndo(n int) {
+	e.maxUndo = n
+	if n <= 0 {
+		e.undoStack = nil
+		e.redoStack = nil
+	}
+}
+
+// recordUndoSnapshot pushes the current buffer onto the undo stack if it
+// differs from the top of the stack, collapsing edits that happen within
+// undoDebounce of each other into a single snapshot.
+func (e *TextEditor) recordUndoSnapshot(now time.Time) {
+	if e.maxUndo <= 0 {
+		return
+	}
+	if n := len(e.undoStack); n > 0 {
+		top := e.undoStack[n-1]
+		if top.text == e.text {
+			return
+		}
+		if now.Sub(e.lastSnapshot) < undoDebounce {
+			e.undoStack[n-1] = undoSnapshot{e.text, e.selStart, e.selEnd}
+			e.lastSnapshot = now
+			return
+		}
+	}
+	e.undoStack = append(e.undoStack, undoSnapshot{e.text, e.selStart, e.selEnd})
+	if len(e.undoStack) > e.maxUndo {
+		e.undoStack = e.undoStack[len(e.undoStack)-e.maxUndo:]
+	}
+	e.redoStack = nil
+	e.lastSnapshot = now
+}
+
+// Undo restores the buffer (and its selection) to the previous snapshot, if
+// any. The live imgui buffer can only be mutated from inside a callback, so
+// Undo just queues the restore; it's applied on the widget's next
+// CallbackAlways invocation (at most one frame later, via applyPendingRestore),
+// the same way recordUndoSnapshot already defers its own bookkeeping.
+func (e *TextEditor) Undo() {
+	if !e.CanUndo() {
+		return
+	}
+	cur := undoSnapshot{e.text, e.selStart, e.selEnd}
+	n := len(e.undoStack)
+	// The top of the undo stack is the current state (recordUndoSnapshot keeps
+	// it so); the previous snapshot is the one below it.
+	snap := e.undoStack[n-2]
+	e.undoStack = e.undoStack[:n-1]
+	e.redoStack = append(e.redoStack, cur)
+	e.pendingRestore = &snap
+}
+
+// Redo reapplies a snapshot previously undone by Undo, if any. Like Undo, it
+// queues the restore for the next CallbackAlways invocation.
+func (e *TextEditor) Redo() {
+	if !e.CanRedo() {
+		return
+	}
+	n := len(e.redoStack)
+	snap := e.redoStack[n-1]
+	e.redoStack = e.redoStack[:n-1]
+	e.undoStack = append(e.undoStack, snap)
+	e.pendingRestore = &snap
+}
+
+// CanUndo reports whether Undo has a snapshot to restore.
+func (e *TextEditor) CanUndo() bool {
+	return len(e.undoStack) > 1
+}
+
+// CanRedo reports whether Redo has a snapshot to restore.
+func (e *TextEditor) CanRedo() bool {
+	return len(e.redoStack) > 0
+}
+
+// applyPendingRestore applies an Undo/Redo queued outside a callback (e.g.
+// from a toolbar button) to the live imgui buffer behind data, if one is
+// pending. It must be called from inside a CallbackAlways invocation.
+func (e *TextEditor) applyPendingRestore(data imgui.InputTextCallbackData) {
+	if e.pendingRestore == nil {
+		return
+	}
+	s := *e.pendingRestore
+	e.pendingRestore = nil
+	e.replaceBuffer(data, s.text, s.selStart, s.selEnd)
+}
+
+// replaceBuffer overwrites the live imgui buffer behind data with s and moves
+// the cursor/selection to [selStart, selEnd). This must run from inside a
+// CallbackAlways/History callback: imgui-go's InputText/InputTextMultiline
+// unconditionally copy the C buffer back over e.text the instant the callback
+// returns (see imgui.go's `defer func(){ *text = state.buf.toGo() }()`), so an
+// assignment to e.text alone would be discarded before the next frame.
+func (e *TextEditor) replaceBuffer(data imgui.InputTextCallbackData, s string, selStart, selEnd int) {
+	data.DeleteBytes(0, len(data.Buffer()))
+	data.InsertBytes(0, []byte(s))
+	data.SetSelectionStart(selStart)
+	data.SetSelectionEnd(selEnd)
+	data.SetCursorPos(selEnd)
+	e.text = s
+	e.selStart = selStart
+	e.selEnd = selEnd
+}
+
+// PushHistory appends s to the submission history used by single-line prompt
+// editors and, if SetHistoryFile was called, persists it to disk.
+func (e *TextEditor) PushHistory(s string) {
+	if s == "" {
+		return
+	}
+	e.history = append(e.history, s)
+	e.historyIdx = len(e.history)
+	if e.historyFile != "" {
+		appendHistoryLine(e.historyFile, s)
+	}
+}
+
+// SetHistoryFile points the editor's submission history at path, loading any
+// entries already there; future PushHistory calls are appended to it. It is a
+// no-op while Password is set, so a widget reused for a credential prompt
+// after SetPassword(true) can't be made to persist masked text to disk; see
+// SetPassword.
+func (e *TextEditor) SetHistoryFile(path string) {
+	if e.password {
+		return
+	}
+	e.historyFile = path
+	if lines, err := readHistoryFile(path); err == nil {
+		e.history = lines
+		e.historyIdx = len(e.history)
+	}
+}
+
+// historyUp moves one entry back in submission history, stashing the
+// in-progress text as the draft the first time it's called.
+func (e *TextEditor) historyUp(data imgui.InputTextCallbackData) {
+	if len(e.history) == 0 || e.historyIdx <= 0 {
+		return
+	}
+	if e.historyIdx == len(e.history) {
+		e.historyDraft = e.text
+	}
+	e.historyIdx--
+	s := e.history[e.historyIdx]
+	e.replaceBuffer(data, s, len(s), len(s))
+}
+
+// historyDown moves one entry forward, restoring the stashed draft once past
+// the newest entry.
+func (e *TextEditor) historyDown(data imgui.InputTextCallbackData) {
+	if e.historyIdx >= len(e.history) {
+		return
+	}
+	e.historyIdx++
+	var next string
+	if e.historyIdx == len(e.history) {
+		next = e.historyDraft
+	} else {
+		next = e.history[e.historyIdx]
+	}
+	e.replaceBuffer(data, next, len(next), len(next))
+}
+
+// handleEditingKeys applies Ctrl-Z/Ctrl-Shift-Z/Ctrl-Y undo/redo, and, for
+// single-line editors, Up/Down submission-history navigation. data is the
+// callback data for the CallbackAlways invocation this is called from; undo,
+// redo, and history restoration are applied through it (see replaceBuffer).
+// While the completion popup is open, Up/Down belong to the popup's own
+// navigation (see buildCompletionPopup), so these keys, and Ctrl-Z/Ctrl-Y
+// with them, are ignored here rather than replacing the in-progress
+// completion prefix out from under the user.
+func (e *TextEditor) handleEditingKeys(data imgui.InputTextCallbackData) {
+	e.applyPendingRestore(data)
+	if e.completionOpen {
+		return
+	}
+	switch {
+	case isCtrlShiftKeyPressed(g.KeyZ):
+		e.Redo()
+	case isCtrlKeyPressed(g.KeyY):
+		e.Redo()
+	case isCtrlKeyPressed(g.KeyZ):
+		e.Undo()
+	}
+	e.applyPendingRestore(data)
+	if !e.multiline {
+		switch {
+		case g.IsKeyPressed(g.KeyUp):
+			e.historyUp(data)
+		case g.IsKeyPressed(g.KeyDown):
+			e.historyDown(data)
+		}
+	}
+}
+
+func isCtrlShiftKeyPressed(key g.Key) bool {
+	shift := g.IsKeyDown(g.KeyLeftShift) || g.IsKeyDown(g.KeyRightShift)
+	return shift && isCtrlKeyPressed(key)
+}
+
+func readHistoryFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+func appendHistoryLine(path, line string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.WriteString(line + "\n")
+}
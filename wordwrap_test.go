@@ -0,0 +1,187 @@
+package giuplus
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// TestGraphemeStepNeverSplitsRunes exercises the same uniseg.Step walk
+// WrapInputtextMultiline uses on mixed Latin/CJK/emoji text and verifies
+// every cluster boundary lands on a rune boundary and the clusters
+// reconstruct the original string exactly.
+func TestGraphemeStepNeverSplitsRunes(t *testing.T) {
+	cases := []string{
+		"hello world",
+		"café latin with combining é accent",
+		"日本語のテキストです",
+		"mixed Latin と 日本語 and emoji 😀",
+		"flag emoji 🇺🇸🇯🇵 in a sentence",
+		"family emoji 👨‍👩‍👧‍👦 with ZWJ",
+	}
+
+	for _, s := range cases {
+		buff := []byte(s)
+		var rebuilt []byte
+		state := -1
+		pos := 0
+		for pos < len(buff) {
+			cluster, _, _, newState := uniseg.Step(buff[pos:], state)
+			state = newState
+			if len(cluster) == 0 {
+				t.Fatalf("%q: empty cluster at pos %d", s, pos)
+			}
+			if !utf8.Valid(cluster) {
+				t.Fatalf("%q: cluster %q at pos %d split a rune", s, cluster, pos)
+			}
+			rebuilt = append(rebuilt, cluster...)
+			pos += len(cluster)
+		}
+		if string(rebuilt) != s {
+			t.Fatalf("clusters did not reconstruct input: got %q, want %q", rebuilt, s)
+		}
+	}
+}
+
+// TestBreakOpportunity checks the minimal UAX #14 subset this package
+// classifies as valid wrap points.
+func TestBreakOpportunity(t *testing.T) {
+	tests := []struct {
+		cluster string
+		isBreak bool
+		consume bool
+	}{
+		{" ", true, true},
+		{"\t", true, true},
+		{"-", true, false},
+		{"中", true, false},
+		{"a", false, false},
+		{"😀", false, false},
+		{"é", false, false},
+	}
+	for _, tt := range tests {
+		isBreak, consume := breakOpportunity(tt.cluster)
+		if isBreak != tt.isBreak || consume != tt.consume {
+			t.Errorf("breakOpportunity(%q) = (%v, %v), want (%v, %v)",
+				tt.cluster, isBreak, consume, tt.isBreak, tt.consume)
+		}
+	}
+}
+
+// runeWidthMeasure is a cheap per-cluster width stand-in for tests: each
+// cluster counts as one unit wide regardless of script, so width budgets can
+// be expressed simply as a cluster count instead of needing a live imgui
+// font context.
+func runeWidthMeasure(cluster string) float32 {
+	return 1
+}
+
+// applyWordWrap mirrors how WrapInputtextMultiline applies planWordWrap's
+// decisions to a live imgui buffer, but against a plain []byte so the whole
+// plan-then-apply path is exercised without any imgui dependency.
+func applyWordWrap(buf []byte, width float32, measure func(string) float32) []byte {
+	breaks := planWordWrap(buf, width, measure)
+	out := append([]byte(nil), buf...)
+	shift := 0
+	for _, br := range breaks {
+		pos := br.pos + shift
+		if br.consume {
+			out[pos] = '\n'
+		} else {
+			out = append(out[:pos], append([]byte{'\n'}, out[pos:]...)...)
+			shift++
+		}
+	}
+	return out
+}
+
+// TestPlanWordWrapBreaksOnClusterBoundaries drives the actual wrap algorithm
+// WrapInputtextMultiline uses (planWordWrap, plus the same apply step) on
+// mixed Latin/CJK/emoji input and checks that every break lands on a
+// grapheme cluster boundary of the input, that the result is valid UTF-8,
+// and that the wrapped lines, joined with spaces ignored, hold exactly the
+// original content.
+func TestPlanWordWrapBreaksOnClusterBoundaries(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		width float32
+	}{
+		{"latin words", "the quick brown fox jumps", 6},
+		{"cjk ideographs", "日本語のテキストです", 3},
+		{"emoji and latin", "mixed Latin と 日本語 and emoji 😀 here", 5},
+		{"flag emoji", "flags 🇺🇸🇯🇵 and more flags 🇫🇷🇩🇪", 4},
+		{"family emoji", "family 👨‍👩‍👧‍👦 emoji with ZWJ sequences", 4},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapped := applyWordWrap([]byte(tc.input), tc.width, runeWidthMeasure)
+
+			boundaries := map[int]bool{len(tc.input): true}
+			ig := uniseg.NewGraphemes(tc.input)
+			for ig.Next() {
+				from, _ := ig.Positions()
+				boundaries[from] = true
+			}
+			for _, br := range planWordWrap([]byte(tc.input), tc.width, runeWidthMeasure) {
+				if !boundaries[br.pos] {
+					t.Fatalf("%q: break at byte %d is inside a grapheme cluster", tc.input, br.pos)
+				}
+			}
+
+			if !utf8.Valid(wrapped) {
+				t.Fatalf("%q: wrap produced invalid UTF-8: %q", tc.input, wrapped)
+			}
+
+			var rebuilt []byte
+			for _, line := range strings.Split(string(wrapped), "\n") {
+				rebuilt = append(rebuilt, []byte(line)...)
+			}
+			plain := strings.ReplaceAll(tc.input, " ", "")
+			gotPlain := strings.ReplaceAll(string(rebuilt), " ", "")
+			// breakOpportunity may consume a space at the break point, so
+			// compare with spaces stripped from both sides; every other byte
+			// must survive untouched.
+			if gotPlain != plain {
+				t.Fatalf("%q: wrapped text lost or altered content: got %q, want %q (ignoring spaces)",
+					tc.input, gotPlain, plain)
+			}
+
+			gr := uniseg.NewGraphemes(string(wrapped))
+			for gr.Next() {
+				if !utf8.ValidString(gr.Str()) {
+					t.Fatalf("%q: wrap point split a grapheme cluster: %q", tc.input, gr.Str())
+				}
+			}
+		})
+	}
+}
+
+// TestPlanWordWrapRespectsWidth checks that no produced line exceeds the
+// requested width once re-measured cluster by cluster.
+func TestPlanWordWrapRespectsWidth(t *testing.T) {
+	input := "aaaa bbbb cccc dddd eeee"
+	width := float32(4)
+	wrapped := applyWordWrap([]byte(input), width, runeWidthMeasure)
+
+	for _, line := range strings.Split(string(wrapped), "\n") {
+		if w := textWidthWith(line, runeWidthMeasure); w > width {
+			t.Errorf("line %q has width %v, want <= %v", line, w, width)
+		}
+	}
+}
+
+func TestIsCJKIdeograph(t *testing.T) {
+	if !isCJKIdeograph("中") {
+		t.Error("expected 中 to be classified as a CJK ideograph")
+	}
+	if isCJKIdeograph("a") {
+		t.Error("did not expect 'a' to be classified as a CJK ideograph")
+	}
+	if isCJKIdeograph("😀") {
+		t.Error("did not expect an emoji cluster to be classified as a CJK ideograph")
+	}
+}